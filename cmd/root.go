@@ -0,0 +1,23 @@
+// Package cmd implements the exercism command-line interface.
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// Out and Err are the writers the commands in this package print to. Tests
+// swap them out for io.Discard (or a buffer) to keep output out of the way
+// and to assert on it.
+var (
+	Out io.Writer = os.Stdout
+	Err io.Writer = os.Stderr
+)
+
+// RootCmd is the entry point for the exercism CLI.
+var RootCmd = &cobra.Command{
+	Use:   "exercism",
+	Short: "A command line tool to interact with Exercism.io",
+}
@@ -0,0 +1,726 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
+	"github.com/exercism/cli/workspace/ignore"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const welcomeMessage = `
+
+    Welcome to Exercism!
+
+    To submit a solution you'll first need to configure the CLI with your
+    API token. Visit
+
+        http://exercism.io/my/settings
+
+    to find it, then run the configure command:
+
+        exercism configure --token=YOUR_TOKEN
+
+`
+
+func init() {
+	RootCmd.AddCommand(newSubmitCmd())
+}
+
+func newSubmitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "submit FILE1 [FILE2 ...]",
+		Short: "Submit your solution to an exercise.",
+		Long: `Submit your solution to an exercise.
+
+Call with one or more file paths to submit just those files. Call with a
+single directory, or with no arguments and --all, to submit every file in
+an exercise directory, skipping anything matched by a .exercismignore file.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSubmit(config.NewConfig(), cmd.Flags(), args)
+		},
+	}
+	cmd.Flags().Bool("all", false, "submit every file in the exercise directory, applying .exercismignore")
+	cmd.Flags().Bool("dry-run", false, "print what would be submitted, without contacting the API")
+	cmd.Flags().String("format", "text", "output format for --dry-run: text or json")
+	cmd.Flags().Bool("changed-only", false, "only upload files that changed since the last submit")
+	return cmd
+}
+
+func runSubmit(cfg config.Config, flags *pflag.FlagSet, args []string) error {
+	usrCfg := cfg.UserViperConfig
+	if usrCfg == nil || usrCfg.GetString("token") == "" {
+		return errors.New(welcomeMessage)
+	}
+
+	fs := cfg.FS()
+
+	ws := usrCfg.GetString("workspace")
+	if ws == "" {
+		return fmt.Errorf("no workspace is configured; please re-run the configure command")
+	}
+	if ok, err := afero.DirExists(fs, ws); err != nil || !ok {
+		return fmt.Errorf("the configured workspace (%s) doesn't exist; please re-run the configure command", ws)
+	}
+
+	all := false
+	dryRun := false
+	changedOnly := false
+	format := "text"
+	if flags != nil {
+		all, _ = flags.GetBool("all")
+		dryRun, _ = flags.GetBool("dry-run")
+		changedOnly, _ = flags.GetBool("changed-only")
+		if f, err := flags.GetString("format"); err == nil && f != "" {
+			format = f
+		}
+	}
+
+	paths, exercise, err := resolveSubmissionPaths(fs, args, all)
+	if err != nil {
+		return err
+	}
+
+	solution, err := exercise.Solution(fs)
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	var toSubmit []string
+	for _, path := range paths {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		info, err := fs.Stat(path)
+		if err != nil {
+			return err
+		}
+		if info.Size() == 0 {
+			fmt.Fprintf(Err, "WARNING: skipping empty file %s\n", path)
+			continue
+		}
+		toSubmit = append(toSubmit, path)
+	}
+	if len(toSubmit) == 0 {
+		return errors.New("No files found to submit.")
+	}
+
+	diff, err := diffAgainstCache(fs, cfg, exercise, solution, toSubmit)
+	if err != nil {
+		return err
+	}
+	if diff.identical {
+		fmt.Fprintln(Out, "No changes to submit.")
+		return nil
+	}
+
+	submitPaths := toSubmit
+	if changedOnly {
+		submitPaths = diff.changed
+	}
+	if len(submitPaths) == 0 {
+		fmt.Fprintln(Out, "No changes to submit.")
+		return nil
+	}
+
+	if dryRun {
+		return printSubmissionPlan(fs, cfg, exercise, solution, submitPaths, format)
+	}
+
+	if err := submitFiles(fs, cfg, exercise, solution, submitPaths); err != nil {
+		return err
+	}
+
+	return diff.recordSubmission(fs, exercise, submitPaths)
+}
+
+// cacheDiff is the result of comparing the files about to be submitted
+// against the exercise's submission cache.
+type cacheDiff struct {
+	cache *workspace.Cache
+
+	// identical is true when every file about to be submitted already
+	// matches the cache, and the cache has no other files left over from a
+	// previous, larger submission.
+	identical bool
+
+	// current is every resolved file's freshly computed cache entry, keyed
+	// by path relative to the exercise root.
+	current map[string]workspace.CacheEntry
+
+	// changed is the subset of the resolved paths whose content isn't
+	// already reflected in the cache.
+	changed []string
+}
+
+// diffAgainstCache hashes paths and compares them against the exercise's
+// existing submission cache, so callers can short-circuit an unchanged
+// resubmission or, with --changed-only, upload just the delta.
+func diffAgainstCache(fs afero.Fs, cfg config.Config, exercise workspace.Exercise, solution *workspace.Solution, paths []string) (*cacheDiff, error) {
+	cache, err := workspace.LoadCache(fs, exercise, solution.ID)
+	if err != nil {
+		return nil, err
+	}
+	if cache.Stale(cfg.CacheTTL) {
+		cache = &workspace.Cache{SolutionID: solution.ID, Files: map[string]workspace.CacheEntry{}}
+	}
+
+	current := map[string]workspace.CacheEntry{}
+	var changed []string
+	for _, path := range paths {
+		rel, err := filepath.Rel(exercise.Root, path)
+		if err != nil {
+			return nil, err
+		}
+		rel = filepath.ToSlash(rel)
+
+		sum, err := workspace.HashFile(fs, path)
+		if err != nil {
+			return nil, err
+		}
+		info, err := fs.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		current[rel] = workspace.CacheEntry{SHA256: sum, Size: info.Size(), ModTime: info.ModTime()}
+		if old, ok := cache.Files[rel]; !ok || old.SHA256 != sum {
+			changed = append(changed, path)
+		}
+	}
+
+	identical := len(changed) == 0 && len(current) == len(cache.Files)
+	return &cacheDiff{cache: cache, identical: identical, current: current, changed: changed}, nil
+}
+
+// recordSubmission updates the submission cache with the outcome of a
+// successful submit: submitted files get a fresh last-submitted-at
+// timestamp, while files that were resolved but skipped by --changed-only
+// keep the one they already had.
+func (d *cacheDiff) recordSubmission(fs afero.Fs, exercise workspace.Exercise, submitted []string) error {
+	now := time.Now()
+	wasSubmitted := map[string]bool{}
+	for _, path := range submitted {
+		rel, err := filepath.Rel(exercise.Root, path)
+		if err != nil {
+			return err
+		}
+		wasSubmitted[filepath.ToSlash(rel)] = true
+	}
+
+	files := map[string]workspace.CacheEntry{}
+	for rel, entry := range d.current {
+		if wasSubmitted[rel] {
+			entry.LastSubmittedAt = now
+		} else if old, ok := d.cache.Files[rel]; ok {
+			entry.LastSubmittedAt = old.LastSubmittedAt
+		}
+		files[rel] = entry
+	}
+
+	d.cache.Files = files
+	return d.cache.Save(fs, exercise)
+}
+
+// resolveSubmissionPaths figures out, from the command-line arguments, which
+// files are being submitted and which exercise they belong to. A single
+// directory argument (or --all) walks the exercise directory and applies
+// .exercismignore; anything else is treated as an explicit list of files, in
+// which case directories are rejected outright.
+func resolveSubmissionPaths(fs afero.Fs, args []string, all bool) ([]string, workspace.Exercise, error) {
+	if all || (len(args) == 1 && isDir(fs, args[0])) {
+		dir := "."
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		return resolveDirSubmission(fs, dir)
+	}
+	return resolveExplicitSubmission(fs, args)
+}
+
+func resolveDirSubmission(fs afero.Fs, dir string) ([]string, workspace.Exercise, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, workspace.Exercise{}, err
+	}
+	info, err := fs.Stat(root)
+	if err != nil {
+		return nil, workspace.Exercise{}, fmt.Errorf("%s cannot be found", dir)
+	}
+	if !info.IsDir() {
+		return nil, workspace.Exercise{}, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	exercise, err := exerciseAt(fs, root)
+	if err != nil {
+		return nil, workspace.Exercise{}, err
+	}
+
+	matcher, err := ignore.LoadTree(fs, root)
+	if err != nil {
+		return nil, workspace.Exercise{}, err
+	}
+
+	var paths []string
+	err = afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if rel == ".exercism" || matcher.Ignores(rel, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Name() == ignore.FileName || matcher.Ignores(rel, false) {
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return nil, workspace.Exercise{}, err
+	}
+	sort.Strings(paths)
+	return paths, exercise, nil
+}
+
+func resolveExplicitSubmission(fs afero.Fs, args []string) ([]string, workspace.Exercise, error) {
+	if len(args) == 0 {
+		return nil, workspace.Exercise{}, errors.New("No files found to submit.")
+	}
+
+	// Validate every argument up front, so that a directory mixed in with
+	// otherwise-fine files is always rejected the same way, regardless of
+	// where in the list it shows up relative to files that happen to have
+	// missing metadata.
+	var paths []string
+	for _, arg := range args {
+		path, err := filepath.Abs(arg)
+		if err != nil {
+			return nil, workspace.Exercise{}, err
+		}
+		info, err := fs.Stat(path)
+		if err != nil {
+			return nil, workspace.Exercise{}, fmt.Errorf("%s cannot be found", arg)
+		}
+		if info.IsDir() {
+			return nil, workspace.Exercise{}, fmt.Errorf("%s is a directory; submitting a directory isn't supported when other files are also given. Pass just the directory, or use --all, to submit everything in it", arg)
+		}
+		paths = append(paths, path)
+	}
+
+	var exercise workspace.Exercise
+	for _, path := range paths {
+		ex, err := workspace.NewExerciseFromFile(fs, path)
+		if err != nil {
+			return nil, workspace.Exercise{}, err
+		}
+		if exercise.Root == "" {
+			exercise = ex
+		} else if exercise.Root != ex.Root {
+			return nil, workspace.Exercise{}, fmt.Errorf("found files from different solutions: %s and %s", exercise.Root, ex.Root)
+		}
+	}
+	return paths, exercise, nil
+}
+
+// exerciseAt confirms that dir has the metadata needed to submit it as a
+// whole, without requiring any individual file within it to be named.
+func exerciseAt(fs afero.Fs, dir string) (workspace.Exercise, error) {
+	ex := workspace.NewExerciseFromDir(dir)
+	if ok, _ := afero.Exists(fs, ex.MetadataFilepath()); ok {
+		return ex, nil
+	}
+	if ok, _ := afero.Exists(fs, ex.LegacyMetadataFilepath()); ok {
+		return ex, nil
+	}
+	return workspace.Exercise{}, fmt.Errorf("%s doesn't have the necessary metadata for submission; please download the exercise again", dir)
+}
+
+func isDir(fs afero.Fs, path string) bool {
+	info, err := fs.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// submissionPlan describes exactly what a submit would post to the API,
+// without actually doing so. It backs both the --dry-run text table and its
+// --format=json equivalent.
+type submissionPlan struct {
+	Track    string     `json:"track"`
+	Exercise string     `json:"exercise"`
+	URL      string     `json:"url"`
+	Files    []planFile `json:"files"`
+}
+
+type planFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+func solutionURL(cfg config.Config, solution *workspace.Solution) string {
+	return fmt.Sprintf("%s/api/v1/solutions/%s", cfg.APIBaseURL(), solution.ID)
+}
+
+// printSubmissionPlan prints what submitFiles would post, sharing the same
+// path resolution and filtering that a real submit goes through, so users
+// can debug "why is this file missing?" without uploading anything.
+func printSubmissionPlan(fs afero.Fs, cfg config.Config, exercise workspace.Exercise, solution *workspace.Solution, paths []string, format string) error {
+	plan := submissionPlan{
+		Track:    exercise.Track,
+		Exercise: exercise.Slug,
+		URL:      solutionURL(cfg, solution),
+	}
+	for _, path := range paths {
+		info, err := fs.Stat(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(exercise.Root, path)
+		if err != nil {
+			return err
+		}
+		plan.Files = append(plan.Files, planFile{Path: filepath.ToSlash(rel), Size: info.Size()})
+	}
+
+	if format == "json" {
+		b, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(Out, string(b))
+		return nil
+	}
+
+	fmt.Fprintf(Out, "Would submit %d file(s) for %s/%s to %s:\n", len(plan.Files), plan.Track, plan.Exercise, plan.URL)
+	for _, f := range plan.Files {
+		fmt.Fprintf(Out, "  %s (%d bytes)\n", f.Path, f.Size)
+	}
+	return nil
+}
+
+const (
+	// maxUploadAttempts bounds how many attempts submitFiles makes in all
+	// (the first try plus retries of a transient failure: network error,
+	// 5xx, 429) before giving up.
+	maxUploadAttempts = 5
+	baseRetryBackoff  = 500 * time.Millisecond
+	maxRetryBackoff   = 8 * time.Second
+
+	// resumeCapabilityHeader is set by servers that support resuming an
+	// interrupted upload via a Content-Range request starting at the byte
+	// offset recorded in the resume state file.
+	resumeCapabilityHeader = "Exercism-Resumable"
+
+	// resumeOffsetHeader carries the number of bytes the server durably
+	// received before a resumable failure, as confirmed by the server
+	// itself. The client's own count of bytes handed to the transport
+	// (progressReporter.uploaded) isn't trustworthy here: buffering or a
+	// partial write on the server side can leave it ahead of what was
+	// actually persisted, which would resume past a gap rather than over it.
+	resumeOffsetHeader = "Exercism-Resume-Offset"
+
+	// spoolFilename is where submitFiles assembles the multipart body
+	// before uploading it, relative to the exercise directory. Spooling to
+	// disk (rather than buffering in memory) keeps memory usage bounded
+	// regardless of how large the submitted files are, and gives retries
+	// and resumption a stable, re-readable body to work from.
+	spoolFilename = ".exercism/upload.tmp"
+)
+
+// retrySleep is time.Sleep by default; tests override it to make retry
+// backoff instant.
+var retrySleep = time.Sleep
+
+func submitFiles(fs afero.Fs, cfg config.Config, exercise workspace.Exercise, solution *workspace.Solution, paths []string) error {
+	spoolPath, size, boundary, err := spoolMultipartBody(fs, exercise, paths)
+	if err != nil {
+		return err
+	}
+	defer fs.Remove(spoolPath)
+
+	offset := int64(0)
+	if resume, err := workspace.LoadResumeState(fs, exercise); err != nil {
+		return err
+	} else if resume != nil && resume.Resumable && resume.SolutionID == solution.ID && resume.TotalSize == size {
+		offset = resume.UploadedBytes
+	}
+
+	url := solutionURL(cfg, solution)
+	contentType := "multipart/form-data; boundary=" + boundary
+	progress := newProgressReporter(Err, exercise, size)
+
+	var lastErr error
+	for attempt := 0; attempt < maxUploadAttempts; attempt++ {
+		res, uploadErr := attemptUpload(fs, spoolPath, size, offset, url, contentType, cfg.Token(), progress)
+
+		switch {
+		case uploadErr != nil:
+			lastErr = uploadErr
+		case res.StatusCode == http.StatusOK:
+			res.Body.Close()
+			if err := workspace.ClearResumeState(fs, exercise); err != nil {
+				return err
+			}
+			fmt.Fprintf(Out, "Submitted %d file(s) for %s/%s\n", len(paths), exercise.Track, exercise.Slug)
+			return nil
+		case !isTransient(res):
+			b, _ := ioutil.ReadAll(res.Body)
+			res.Body.Close()
+			return fmt.Errorf("failed to submit: %s", string(b))
+		default:
+			lastErr = fmt.Errorf("failed to submit: %s", res.Status)
+			if res.Header.Get(resumeCapabilityHeader) != "" {
+				if confirmed, ok := parseResumeOffset(res.Header.Get(resumeOffsetHeader)); ok {
+					if err := workspace.SaveResumeState(fs, exercise, solution.ID, size, confirmed, true); err != nil {
+						res.Body.Close()
+						return err
+					}
+					// Resume the next attempt from the server-confirmed
+					// offset, rather than re-reading the resume state we
+					// just saved.
+					offset = confirmed
+				}
+			}
+			res.Body.Close()
+		}
+
+		if attempt == maxUploadAttempts-1 {
+			break
+		}
+		fmt.Fprintf(Err, "\nUpload failed (%s), retrying...\n", lastErr)
+		retrySleep(backoffDuration(attempt, res))
+	}
+
+	return fmt.Errorf("failed to submit after %d attempts: %s", maxUploadAttempts, lastErr)
+}
+
+// spoolMultipartBody streams paths into a multipart body using an io.Pipe,
+// writing it to a temporary file under the exercise's .exercism directory
+// rather than buffering it in memory. It returns the spool file's path,
+// its total size, and the multipart boundary used to build it.
+//
+// Each file's relative path (which may include subdirectories) is carried
+// as its form field name rather than its filename: mime/multipart runs the
+// filename parameter through filepath.Base on read (RFC 7578 §4.2), which
+// would silently drop any subdirectory a "files[]"-style fixed field name
+// relied on to disambiguate files. Field names aren't sanitized the same
+// way, so the server recovers the relative path from the field name.
+func spoolMultipartBody(fs afero.Fs, exercise workspace.Exercise, paths []string) (path string, size int64, boundary string, err error) {
+	path = filepath.Join(exercise.Root, spoolFilename)
+	if err = fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", 0, "", err
+	}
+	out, err := fs.Create(path)
+	if err != nil {
+		return "", 0, "", err
+	}
+	defer out.Close()
+
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+	boundary = w.Boundary()
+
+	go func() {
+		for _, p := range paths {
+			rel, relErr := filepath.Rel(exercise.Root, p)
+			if relErr != nil {
+				pw.CloseWithError(relErr)
+				return
+			}
+			rel = filepath.ToSlash(rel)
+
+			part, partErr := w.CreateFormFile(rel, filepath.Base(rel))
+			if partErr != nil {
+				pw.CloseWithError(partErr)
+				return
+			}
+			if copyErr := copyFileInto(fs, part, p); copyErr != nil {
+				pw.CloseWithError(copyErr)
+				return
+			}
+		}
+		if closeErr := w.Close(); closeErr != nil {
+			pw.CloseWithError(closeErr)
+			return
+		}
+		pw.Close()
+	}()
+
+	size, err = io.Copy(out, pr)
+	if err != nil {
+		pr.CloseWithError(err)
+		return "", 0, "", err
+	}
+	return path, size, boundary, nil
+}
+
+// parseResumeOffset parses the server-confirmed byte offset out of a
+// resumable failure response. ok is false if the header is missing or
+// isn't a valid non-negative integer, in which case the caller has no
+// trustworthy offset to resume from.
+func parseResumeOffset(v string) (offset int64, ok bool) {
+	if v == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// attemptUpload sends (or resends, starting at offset) the spooled
+// multipart body in a single request. offset > 0 marks it as a resumed
+// Content-Range request.
+func attemptUpload(fs afero.Fs, spoolPath string, size, offset int64, url, contentType, token string, progress *progressReporter) (*http.Response, error) {
+	f, err := fs.Open(spoolPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if offset > 0 {
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodPatch, url, progress.track(f, offset))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size - offset
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if offset > 0 {
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, size-1, size))
+	}
+
+	return http.DefaultClient.Do(req)
+}
+
+// isTransient reports whether a failed response is worth retrying: a
+// network error (res == nil), a 429 (respected via Retry-After), or a 5xx.
+func isTransient(res *http.Response) bool {
+	if res == nil {
+		return true
+	}
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError
+}
+
+// backoffDuration honors a Retry-After header when present, otherwise
+// backs off exponentially from baseRetryBackoff, capped at maxRetryBackoff.
+func backoffDuration(attempt int, res *http.Response) time.Duration {
+	if d := retryAfter(res); d > 0 {
+		return d
+	}
+	d := baseRetryBackoff * time.Duration(int64(1)<<uint(attempt))
+	if d > maxRetryBackoff {
+		d = maxRetryBackoff
+	}
+	return d
+}
+
+func retryAfter(res *http.Response) time.Duration {
+	if res == nil {
+		return 0
+	}
+	v := res.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// progressReporter writes upload progress for a single exercise to an
+// io.Writer (Err, in production), as a percentage of the total payload
+// size that's been read off disk and handed to the HTTP client so far.
+type progressReporter struct {
+	out      io.Writer
+	label    string
+	total    int64
+	uploaded int64
+	lastPct  int
+}
+
+func newProgressReporter(out io.Writer, exercise workspace.Exercise, total int64) *progressReporter {
+	return &progressReporter{out: out, label: fmt.Sprintf("%s/%s", exercise.Track, exercise.Slug), total: total, lastPct: -1}
+}
+
+// track wraps r so that reading from it updates the reporter, starting
+// from an upload that's already gotten as far as `start` bytes in.
+func (p *progressReporter) track(r io.Reader, start int64) io.Reader {
+	p.uploaded = start
+	return &progressTrackingReader{r: r, p: p}
+}
+
+func (p *progressReporter) report() {
+	if p.total <= 0 {
+		return
+	}
+	pct := int(p.uploaded * 100 / p.total)
+	if pct == p.lastPct {
+		return
+	}
+	p.lastPct = pct
+	fmt.Fprintf(p.out, "\rSubmitting %s: %d%%", p.label, pct)
+	if pct == 100 {
+		fmt.Fprintln(p.out)
+	}
+}
+
+type progressTrackingReader struct {
+	r io.Reader
+	p *progressReporter
+}
+
+func (t *progressTrackingReader) Read(b []byte) (int, error) {
+	n, err := t.r.Read(b)
+	if n > 0 {
+		t.p.uploaded += int64(n)
+		t.p.report()
+	}
+	return n, err
+}
+
+func copyFileInto(fs afero.Fs, w io.Writer, path string) error {
+	f, err := fs.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
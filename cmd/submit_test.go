@@ -1,16 +1,21 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/exercism/cli/config"
 	"github.com/exercism/cli/workspace"
+	"github.com/spf13/afero"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -146,7 +151,7 @@ func TestSubmitFiles(t *testing.T) {
 
 	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
 	os.MkdirAll(filepath.Join(dir, "subdir"), os.FileMode(0755))
-	writeFakeSolution(t, dir, "bogus-track", "bogus-exercise")
+	writeFakeSolution(t, afero.NewOsFs(), dir, "bogus-track", "bogus-exercise")
 
 	file1 := filepath.Join(dir, "file-1.txt")
 	err = ioutil.WriteFile(file1, []byte("This is file 1."), os.FileMode(0755))
@@ -199,12 +204,11 @@ func TestLegacySolutionMetadataMigration(t *testing.T) {
 	ts := fakeSubmitServer(t, submittedFiles)
 	defer ts.Close()
 
-	tmpDir, err := ioutil.TempDir("", "legacy-metadata-file")
-	defer os.RemoveAll(tmpDir)
-	assert.NoError(t, err)
+	fs := afero.NewMemMapFs()
+	tmpDir := "/legacy-metadata-file"
 
 	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
-	os.MkdirAll(dir, os.FileMode(0755))
+	assert.NoError(t, fs.MkdirAll(dir, os.FileMode(0755)))
 
 	// Write fake legacy solution
 	solution := &workspace.Solution{
@@ -217,11 +221,11 @@ func TestLegacySolutionMetadataMigration(t *testing.T) {
 	b, err := json.Marshal(solution)
 	assert.NoError(t, err)
 	exercise := workspace.NewExerciseFromDir(dir)
-	err = ioutil.WriteFile(exercise.LegacyMetadataFilepath(), b, os.FileMode(0600))
+	err = afero.WriteFile(fs, exercise.LegacyMetadataFilepath(), b, os.FileMode(0600))
 	assert.NoError(t, err)
 
 	file := filepath.Join(dir, "file.txt")
-	err = ioutil.WriteFile(file, []byte("This is a file."), os.FileMode(0755))
+	err = afero.WriteFile(fs, file, []byte("This is a file."), os.FileMode(0755))
 	assert.NoError(t, err)
 
 	v := viper.New()
@@ -232,19 +236,23 @@ func TestLegacySolutionMetadataMigration(t *testing.T) {
 		Persister:       config.InMemoryPersister{},
 		Dir:             tmpDir,
 		UserViperConfig: v,
+		Fs:              fs,
 	}
 	expectedPathAfterMigration := exercise.MetadataFilepath()
-	_, err = os.Stat(expectedPathAfterMigration)
-	assert.Error(t, err)
+	ok, err := afero.Exists(fs, expectedPathAfterMigration)
+	assert.NoError(t, err)
+	assert.False(t, ok)
 
 	err = runSubmit(cfg, pflag.NewFlagSet("fake", pflag.PanicOnError), []string{file})
 	assert.NoError(t, err)
 	assert.Equal(t, "This is a file.", submittedFiles["file.txt"])
 
-	_, err = os.Stat(expectedPathAfterMigration)
+	ok, err = afero.Exists(fs, expectedPathAfterMigration)
 	assert.NoError(t, err)
-	_, err = os.Stat(exercise.LegacyMetadataFilepath())
-	assert.Error(t, err)
+	assert.True(t, ok)
+	ok, err = afero.Exists(fs, exercise.LegacyMetadataFilepath())
+	assert.NoError(t, err)
+	assert.False(t, ok)
 }
 
 func TestSubmitWithEmptyFile(t *testing.T) {
@@ -269,7 +277,7 @@ func TestSubmitWithEmptyFile(t *testing.T) {
 	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
 	os.MkdirAll(dir, os.FileMode(0755))
 
-	writeFakeSolution(t, dir, "bogus-track", "bogus-exercise")
+	writeFakeSolution(t, afero.NewOsFs(), dir, "bogus-track", "bogus-exercise")
 
 	v := viper.New()
 	v.Set("token", "abc123")
@@ -312,7 +320,7 @@ func TestSubmitFilesForTeamExercise(t *testing.T) {
 
 	dir := filepath.Join(tmpDir, "teams", "bogus-team", "bogus-track", "bogus-exercise")
 	os.MkdirAll(filepath.Join(dir, "subdir"), os.FileMode(0755))
-	writeFakeSolution(t, dir, "bogus-track", "bogus-exercise")
+	writeFakeSolution(t, afero.NewOsFs(), dir, "bogus-track", "bogus-exercise")
 
 	file1 := filepath.Join(dir, "file-1.txt")
 	err = ioutil.WriteFile(file1, []byte("This is file 1."), os.FileMode(0755))
@@ -361,7 +369,7 @@ func TestSubmitOnlyEmptyFile(t *testing.T) {
 	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
 	os.MkdirAll(dir, os.FileMode(0755))
 
-	writeFakeSolution(t, dir, "bogus-track", "bogus-exercise")
+	writeFakeSolution(t, afero.NewOsFs(), dir, "bogus-track", "bogus-exercise")
 
 	v := viper.New()
 	v.Set("token", "abc123")
@@ -387,11 +395,11 @@ func TestSubmitFilesFromDifferentSolutions(t *testing.T) {
 
 	dir1 := filepath.Join(tmpDir, "bogus-track", "bogus-exercise-1")
 	os.MkdirAll(dir1, os.FileMode(0755))
-	writeFakeSolution(t, dir1, "bogus-track", "bogus-exercise-1")
+	writeFakeSolution(t, afero.NewOsFs(), dir1, "bogus-track", "bogus-exercise-1")
 
 	dir2 := filepath.Join(tmpDir, "bogus-track", "bogus-exercise-2")
 	os.MkdirAll(dir2, os.FileMode(0755))
-	writeFakeSolution(t, dir2, "bogus-track", "bogus-exercise-2")
+	writeFakeSolution(t, afero.NewOsFs(), dir2, "bogus-track", "bogus-exercise-2")
 
 	file1 := filepath.Join(dir1, "file-1.txt")
 	err = ioutil.WriteFile(file1, []byte("This is file 1."), os.FileMode(0755))
@@ -416,16 +424,15 @@ func TestSubmitFilesFromDifferentSolutions(t *testing.T) {
 	assert.Regexp(t, "different solutions", err.Error())
 }
 
-func fakeSubmitServer(t *testing.T, submittedFiles map[string]string) *httptest.Server {
-	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		err := r.ParseMultipartForm(2 << 10)
-		if err != nil {
-			t.Fatal(err)
-		}
-		mf := r.MultipartForm
-
-		files := mf.File["files[]"]
-		for _, fileHeader := range files {
+// submittedMultipartFiles reads every file part of a submission into
+// submittedFiles, keyed by the part's form field name rather than its
+// filename: mime/multipart runs FileHeader.Filename through filepath.Base
+// on read (RFC 7578 §4.2), which would collapse any subdirectory the
+// client encoded there, so the client instead encodes each file's
+// exercise-relative path as its field name.
+func submittedMultipartFiles(t *testing.T, mf *multipart.Form, submittedFiles map[string]string) {
+	for fieldName, headers := range mf.File {
+		for _, fileHeader := range headers {
 			file, err := fileHeader.Open()
 			if err != nil {
 				t.Fatal(err)
@@ -435,8 +442,33 @@ func fakeSubmitServer(t *testing.T, submittedFiles map[string]string) *httptest.
 			if err != nil {
 				t.Fatal(err)
 			}
-			submittedFiles[fileHeader.Filename] = string(body)
+			submittedFiles[fieldName] = string(body)
+		}
+	}
+}
+
+func fakeSubmitServer(t *testing.T, submittedFiles map[string]string) *httptest.Server {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseMultipartForm(2 << 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		submittedMultipartFiles(t, r.MultipartForm, submittedFiles)
+	})
+	return httptest.NewServer(handler)
+}
+
+// countingSubmitServer behaves like fakeSubmitServer, but also tallies how
+// many requests it received in requestCount, so tests can assert that a
+// cache hit never talks to the API at all.
+func countingSubmitServer(t *testing.T, submittedFiles map[string]string, requestCount *int) *httptest.Server {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requestCount++
+		err := r.ParseMultipartForm(2 << 10)
+		if err != nil {
+			t.Fatal(err)
 		}
+		submittedMultipartFiles(t, r.MultipartForm, submittedFiles)
 	})
 	return httptest.NewServer(handler)
 }
@@ -462,7 +494,7 @@ func TestSubmitRelativePath(t *testing.T) {
 	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
 	os.MkdirAll(dir, os.FileMode(0755))
 
-	writeFakeSolution(t, dir, "bogus-track", "bogus-exercise")
+	writeFakeSolution(t, afero.NewOsFs(), dir, "bogus-track", "bogus-exercise")
 
 	v := viper.New()
 	v.Set("token", "abc123")
@@ -476,6 +508,10 @@ func TestSubmitRelativePath(t *testing.T) {
 
 	err = ioutil.WriteFile(filepath.Join(dir, "file.txt"), []byte("This is a file."), os.FileMode(0755))
 
+	oldWd, err := os.Getwd()
+	assert.NoError(t, err)
+	defer os.Chdir(oldWd)
+
 	err = os.Chdir(dir)
 	assert.NoError(t, err)
 
@@ -486,7 +522,7 @@ func TestSubmitRelativePath(t *testing.T) {
 	assert.Equal(t, "This is a file.", submittedFiles["file.txt"])
 }
 
-func writeFakeSolution(t *testing.T, dir, trackID, exerciseSlug string) {
+func writeFakeSolution(t *testing.T, fs afero.Fs, dir, trackID, exerciseSlug string) {
 	solution := &workspace.Solution{
 		ID:          "bogus-solution-uuid",
 		Track:       trackID,
@@ -494,6 +530,556 @@ func writeFakeSolution(t *testing.T, dir, trackID, exerciseSlug string) {
 		URL:         "http://example.com/bogus-url",
 		IsRequester: true,
 	}
-	err := solution.Write(dir)
+	err := solution.Write(fs, dir)
+	assert.NoError(t, err)
+}
+
+func TestSubmitCacheSkipsUnchangedResubmission(t *testing.T) {
+	oldOut := Out
+	oldErr := Err
+	Out = ioutil.Discard
+	Err = ioutil.Discard
+	defer func() {
+		Out = oldOut
+		Err = oldErr
+	}()
+	submittedFiles := map[string]string{}
+	requestCount := 0
+	ts := countingSubmitServer(t, submittedFiles, &requestCount)
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "submit-cache")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	os.MkdirAll(dir, os.FileMode(0755))
+	writeFakeSolution(t, afero.NewOsFs(), dir, "bogus-track", "bogus-exercise")
+
+	file1 := filepath.Join(dir, "file-1.txt")
+	err = ioutil.WriteFile(file1, []byte("This is file 1."), os.FileMode(0755))
+	assert.NoError(t, err)
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+
+	cfg := config.Config{
+		Persister:       config.InMemoryPersister{},
+		Dir:             tmpDir,
+		UserViperConfig: v,
+	}
+
+	files := []string{file1}
+
+	err = runSubmit(cfg, pflag.NewFlagSet("fake", pflag.PanicOnError), files)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requestCount)
+	assert.Equal(t, "This is file 1.", submittedFiles["file-1.txt"])
+
+	// Re-running against byte-identical content must not talk to the API.
+	err = runSubmit(cfg, pflag.NewFlagSet("fake", pflag.PanicOnError), files)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requestCount, "unchanged resubmission should make zero additional requests")
+}
+
+func TestSubmitChangedOnlyUploadsTheDelta(t *testing.T) {
+	oldOut := Out
+	oldErr := Err
+	Out = ioutil.Discard
+	Err = ioutil.Discard
+	defer func() {
+		Out = oldOut
+		Err = oldErr
+	}()
+	submittedFiles := map[string]string{}
+	requestCount := 0
+	ts := countingSubmitServer(t, submittedFiles, &requestCount)
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "submit-changed-only")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	os.MkdirAll(dir, os.FileMode(0755))
+	writeFakeSolution(t, afero.NewOsFs(), dir, "bogus-track", "bogus-exercise")
+
+	file1 := filepath.Join(dir, "file-1.txt")
+	assert.NoError(t, ioutil.WriteFile(file1, []byte("This is file 1."), os.FileMode(0755)))
+	file2 := filepath.Join(dir, "file-2.txt")
+	assert.NoError(t, ioutil.WriteFile(file2, []byte("This is file 2."), os.FileMode(0755)))
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+
+	cfg := config.Config{
+		Persister:       config.InMemoryPersister{},
+		Dir:             tmpDir,
+		UserViperConfig: v,
+	}
+
+	files := []string{file1, file2}
+	assert.NoError(t, runSubmit(cfg, pflag.NewFlagSet("fake", pflag.PanicOnError), files))
+	assert.Equal(t, 2, len(submittedFiles))
+
+	// Touch only file2, then resubmit with --changed-only: only file2 should
+	// go over the wire this time.
+	assert.NoError(t, ioutil.WriteFile(file2, []byte("This is file 2, edited."), os.FileMode(0755)))
+	// Clear in place: countingSubmitServer's handler closed over this map
+	// instance, so reassigning submittedFiles here would leave the handler
+	// writing into the old map while we read the new, empty one.
+	for k := range submittedFiles {
+		delete(submittedFiles, k)
+	}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	flags.Bool("changed-only", false, "")
+	assert.NoError(t, flags.Set("changed-only", "true"))
+
+	assert.NoError(t, runSubmit(cfg, flags, files))
+	assert.Equal(t, 1, len(submittedFiles))
+	assert.Equal(t, "This is file 2, edited.", submittedFiles["file-2.txt"])
+}
+
+// TestSubmitChangedOnlySkipsUnchangedSubset resolves to a subset of a
+// previous, larger submission whose files are all still unchanged. That
+// makes diff.identical false (the resolved file count no longer matches the
+// cache's), but diff.changed is still empty, so no request should be made.
+func TestSubmitChangedOnlySkipsUnchangedSubset(t *testing.T) {
+	oldOut := Out
+	oldErr := Err
+	Out = ioutil.Discard
+	Err = ioutil.Discard
+	defer func() {
+		Out = oldOut
+		Err = oldErr
+	}()
+	submittedFiles := map[string]string{}
+	requestCount := 0
+	ts := countingSubmitServer(t, submittedFiles, &requestCount)
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "submit-changed-only-subset")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	os.MkdirAll(dir, os.FileMode(0755))
+	writeFakeSolution(t, afero.NewOsFs(), dir, "bogus-track", "bogus-exercise")
+
+	file1 := filepath.Join(dir, "file-1.txt")
+	assert.NoError(t, ioutil.WriteFile(file1, []byte("This is file 1."), os.FileMode(0755)))
+	file2 := filepath.Join(dir, "file-2.txt")
+	assert.NoError(t, ioutil.WriteFile(file2, []byte("This is file 2."), os.FileMode(0755)))
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+
+	cfg := config.Config{
+		Persister:       config.InMemoryPersister{},
+		Dir:             tmpDir,
+		UserViperConfig: v,
+	}
+
+	assert.NoError(t, runSubmit(cfg, pflag.NewFlagSet("fake", pflag.PanicOnError), []string{file1, file2}))
+	assert.Equal(t, 1, requestCount)
+
+	// Resubmit just file1, unchanged, with --changed-only: the resolved
+	// subset differs in size from the cache's (2 files), but nothing in it
+	// actually changed, so this must not talk to the API at all.
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	flags.Bool("changed-only", false, "")
+	assert.NoError(t, flags.Set("changed-only", "true"))
+
+	assert.NoError(t, runSubmit(cfg, flags, []string{file1}))
+	assert.Equal(t, 1, requestCount, "resubmitting an unchanged subset should make zero additional requests")
+}
+
+// flakySubmitServer answers the first `failures` requests with a 500, then
+// behaves like fakeSubmitServer.
+func flakySubmitServer(t *testing.T, submittedFiles map[string]string, requestCount *int, failures int) *httptest.Server {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requestCount++
+		if *requestCount <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		err := r.ParseMultipartForm(2 << 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		submittedMultipartFiles(t, r.MultipartForm, submittedFiles)
+	})
+	return httptest.NewServer(handler)
+}
+
+func TestSubmitRetriesTransientFailures(t *testing.T) {
+	oldOut, oldErr := Out, Err
+	Out = ioutil.Discard
+	Err = ioutil.Discard
+	defer func() { Out = oldOut; Err = oldErr }()
+
+	oldSleep := retrySleep
+	retrySleep = func(time.Duration) {}
+	defer func() { retrySleep = oldSleep }()
+
+	submittedFiles := map[string]string{}
+	requestCount := 0
+	ts := flakySubmitServer(t, submittedFiles, &requestCount, 1)
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "submit-retry")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	os.MkdirAll(dir, os.FileMode(0755))
+	writeFakeSolution(t, afero.NewOsFs(), dir, "bogus-track", "bogus-exercise")
+
+	file1 := filepath.Join(dir, "file-1.txt")
+	assert.NoError(t, ioutil.WriteFile(file1, []byte("This is file 1."), os.FileMode(0755)))
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+
+	cfg := config.Config{
+		Persister:       config.InMemoryPersister{},
+		Dir:             tmpDir,
+		UserViperConfig: v,
+	}
+
+	err = runSubmit(cfg, pflag.NewFlagSet("fake", pflag.PanicOnError), []string{file1})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requestCount, "should retry once after the first 500")
+	assert.Equal(t, "This is file 1.", submittedFiles["file-1.txt"])
+}
+
+func TestSubmitResumesAfterPartialUploadAbort(t *testing.T) {
+	oldOut, oldErr := Out, Err
+	Out = ioutil.Discard
+	Err = ioutil.Discard
+	defer func() { Out = oldOut; Err = oldErr }()
+
+	oldSleep := retrySleep
+	retrySleep = func(time.Duration) {}
+	defer func() { retrySleep = oldSleep }()
+
+	requestCount := 0
+	var secondRequestRange string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if requestCount == 1 {
+			// Simulate an upload the server had to abort partway through,
+			// but that it knows how to resume from the bytes it durably
+			// received, and reports back exactly how many that was.
+			io.CopyN(ioutil.Discard, r.Body, 16)
+			w.Header().Set("Exercism-Resumable", "bytes")
+			w.Header().Set("Exercism-Resume-Offset", "16")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		secondRequestRange = r.Header.Get("Content-Range")
+		ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "submit-resume")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	os.MkdirAll(dir, os.FileMode(0755))
+	writeFakeSolution(t, afero.NewOsFs(), dir, "bogus-track", "bogus-exercise")
+
+	file1 := filepath.Join(dir, "file-1.txt")
+	assert.NoError(t, ioutil.WriteFile(file1, []byte("This is file 1, long enough to span a resumed range."), os.FileMode(0755)))
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+
+	cfg := config.Config{
+		Persister:       config.InMemoryPersister{},
+		Dir:             tmpDir,
+		UserViperConfig: v,
+	}
+
+	err = runSubmit(cfg, pflag.NewFlagSet("fake", pflag.PanicOnError), []string{file1})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requestCount)
+	assert.Regexp(t, `^bytes 16-\d+/\d+$`, secondRequestRange, "the retried request should resume from the server-confirmed offset")
+
+	exercise := workspace.NewExerciseFromDir(dir)
+	resume, err := workspace.LoadResumeState(afero.NewOsFs(), exercise)
+	assert.NoError(t, err)
+	assert.Nil(t, resume, "resume state should be cleared after a successful submit")
+}
+
+func TestSubmitDryRun(t *testing.T) {
+	oldOut := Out
+	oldErr := Err
+	buf := &bytes.Buffer{}
+	Out = buf
+	Err = ioutil.Discard
+	defer func() {
+		Out = oldOut
+		Err = oldErr
+	}()
+
+	requestCount := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+	}))
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "submit-dry-run")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	os.MkdirAll(filepath.Join(dir, "subdir"), os.FileMode(0755))
+	writeFakeSolution(t, afero.NewOsFs(), dir, "bogus-track", "bogus-exercise")
+
+	file1 := filepath.Join(dir, "file-1.txt")
+	assert.NoError(t, ioutil.WriteFile(file1, []byte("This is file 1."), os.FileMode(0755)))
+
+	file2 := filepath.Join(dir, "subdir", "file-2.txt")
+	assert.NoError(t, ioutil.WriteFile(file2, []byte("This is file 2."), os.FileMode(0755)))
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+
+	cfg := config.Config{
+		Persister:       config.InMemoryPersister{},
+		UserViperConfig: v,
+	}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	flags.Bool("dry-run", true, "")
+	flags.String("format", "text", "")
+
+	err = runSubmit(cfg, flags, []string{file1, file2})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 0, requestCount)
+	assert.Regexp(t, "file-1.txt", buf.String())
+	assert.Regexp(t, "subdir/file-2.txt", buf.String())
+}
+
+func TestSubmitDryRunJSON(t *testing.T) {
+	oldOut := Out
+	oldErr := Err
+	buf := &bytes.Buffer{}
+	Out = buf
+	Err = ioutil.Discard
+	defer func() {
+		Out = oldOut
+		Err = oldErr
+	}()
+
+	requestCount := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+	}))
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "submit-dry-run-json")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	os.MkdirAll(dir, os.FileMode(0755))
+	writeFakeSolution(t, afero.NewOsFs(), dir, "bogus-track", "bogus-exercise")
+
+	file := filepath.Join(dir, "file.txt")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("This is a file."), os.FileMode(0755)))
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+
+	cfg := config.Config{
+		Persister:       config.InMemoryPersister{},
+		UserViperConfig: v,
+	}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	flags.Bool("dry-run", true, "")
+	flags.String("format", "json", "")
+
+	err = runSubmit(cfg, flags, []string{file})
 	assert.NoError(t, err)
+
+	assert.Equal(t, 0, requestCount)
+
+	var plan struct {
+		Track    string `json:"track"`
+		Exercise string `json:"exercise"`
+		URL      string `json:"url"`
+		Files    []struct {
+			Path string `json:"path"`
+			Size int64  `json:"size"`
+		} `json:"files"`
+	}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &plan))
+	assert.Equal(t, "bogus-track", plan.Track)
+	assert.Equal(t, "bogus-exercise", plan.Exercise)
+	assert.Equal(t, 1, len(plan.Files))
+	assert.Equal(t, "file.txt", plan.Files[0].Path)
+	assert.Equal(t, int64(len("This is a file.")), plan.Files[0].Size)
+}
+
+func TestSubmitDirectoryWithIgnoreFile(t *testing.T) {
+	oldOut := Out
+	oldErr := Err
+	Out = ioutil.Discard
+	Err = ioutil.Discard
+	defer func() {
+		Out = oldOut
+		Err = oldErr
+	}()
+	submittedFiles := map[string]string{}
+	ts := fakeSubmitServer(t, submittedFiles)
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "submit-dir")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	os.MkdirAll(filepath.Join(dir, "vendor"), os.FileMode(0755))
+	writeFakeSolution(t, afero.NewOsFs(), dir, "bogus-track", "bogus-exercise")
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "solution.go"), []byte("package main"), os.FileMode(0644)))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "solution_test.go"), []byte("package main"), os.FileMode(0644)))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "vendor", "dep.go"), []byte("package vendor"), os.FileMode(0644)))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, ".exercismignore"), []byte("vendor/\n*_test.go\n"), os.FileMode(0644)))
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+
+	cfg := config.Config{
+		Persister:       config.InMemoryPersister{},
+		UserViperConfig: v,
+	}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	flags.Bool("all", true, "")
+
+	err = runSubmit(cfg, flags, []string{dir})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(submittedFiles))
+	assert.Equal(t, "package main", submittedFiles["solution.go"])
+}
+
+func TestSubmitAllFlagFromWorkingDirectory(t *testing.T) {
+	oldOut := Out
+	oldErr := Err
+	Out = ioutil.Discard
+	Err = ioutil.Discard
+	defer func() {
+		Out = oldOut
+		Err = oldErr
+	}()
+	submittedFiles := map[string]string{}
+	ts := fakeSubmitServer(t, submittedFiles)
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "submit-all")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	os.MkdirAll(dir, os.FileMode(0755))
+	writeFakeSolution(t, afero.NewOsFs(), dir, "bogus-track", "bogus-exercise")
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "solution.go"), []byte("package main"), os.FileMode(0644)))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "notes.md"), []byte("ignore me"), os.FileMode(0644)))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, ".exercismignore"), []byte("*.md\n"), os.FileMode(0644)))
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+
+	cfg := config.Config{
+		Persister:       config.InMemoryPersister{},
+		UserViperConfig: v,
+	}
+
+	oldWd, err := os.Getwd()
+	assert.NoError(t, err)
+	defer os.Chdir(oldWd)
+	assert.NoError(t, os.Chdir(dir))
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	flags.Bool("all", true, "")
+
+	err = runSubmit(cfg, flags, []string{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(submittedFiles))
+	assert.Equal(t, "package main", submittedFiles["solution.go"])
+}
+
+func TestSubmitExplicitFileBypassesIgnoreFile(t *testing.T) {
+	oldOut := Out
+	oldErr := Err
+	Out = ioutil.Discard
+	Err = ioutil.Discard
+	defer func() {
+		Out = oldOut
+		Err = oldErr
+	}()
+	submittedFiles := map[string]string{}
+	ts := fakeSubmitServer(t, submittedFiles)
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "submit-bypass-ignore")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	os.MkdirAll(dir, os.FileMode(0755))
+	writeFakeSolution(t, afero.NewOsFs(), dir, "bogus-track", "bogus-exercise")
+
+	readme := filepath.Join(dir, "README.md")
+	assert.NoError(t, ioutil.WriteFile(readme, []byte("This is the readme."), os.FileMode(0644)))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, ".exercismignore"), []byte("*.md\n"), os.FileMode(0644)))
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+
+	cfg := config.Config{
+		Persister:       config.InMemoryPersister{},
+		UserViperConfig: v,
+	}
+
+	// Passing README.md explicitly still submits it, even though a
+	// .exercismignore in the same directory would otherwise filter it out
+	// of an --all or directory submission.
+	err = runSubmit(cfg, pflag.NewFlagSet("fake", pflag.PanicOnError), []string{readme})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(submittedFiles))
+	assert.Equal(t, "This is the readme.", submittedFiles["README.md"])
 }
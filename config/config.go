@@ -0,0 +1,94 @@
+// Package config holds the user- and environment-specific settings that the
+// CLI commands need in order to talk to the Exercism API and the local
+// workspace.
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+)
+
+// Config bundles the pieces of configuration that commands depend on. It is
+// passed explicitly into the run* functions (rather than read from package
+// globals) so that tests can supply fakes.
+type Config struct {
+	Persister       Persister
+	UserViperConfig *viper.Viper
+	DefaultBaseURL  string
+	Dir             string
+
+	// Fs is the filesystem commands read solutions and exercises from. It
+	// defaults to the real filesystem; tests can swap in an
+	// afero.NewMemMapFs() to avoid touching disk.
+	Fs afero.Fs
+
+	// CacheTTL is how long a submission cache entry may be trusted before
+	// it's discarded, regardless of whether its contents still look valid.
+	// Zero disables expiry.
+	CacheTTL time.Duration
+}
+
+// FS returns c.Fs, falling back to the real filesystem if the caller didn't
+// set one. Call this instead of reading c.Fs directly so zero-value Configs
+// keep working.
+func (c Config) FS() afero.Fs {
+	if c.Fs == nil {
+		return afero.NewOsFs()
+	}
+	return c.Fs
+}
+
+// Workspace is the root directory the user has configured for downloaded
+// exercises and solutions.
+func (c Config) Workspace() string {
+	if c.UserViperConfig == nil {
+		return ""
+	}
+	return c.UserViperConfig.GetString("workspace")
+}
+
+// Token is the user's Exercism API token.
+func (c Config) Token() string {
+	if c.UserViperConfig == nil {
+		return ""
+	}
+	return c.UserViperConfig.GetString("token")
+}
+
+// APIBaseURL is the base URL to use when talking to the Exercism API. A
+// value configured by the user takes precedence over the build-time default.
+func (c Config) APIBaseURL() string {
+	if c.UserViperConfig != nil {
+		if url := c.UserViperConfig.GetString("apibaseurl"); url != "" {
+			return url
+		}
+	}
+	return c.DefaultBaseURL
+}
+
+// defaultBaseURL is where the CLI talks to the Exercism API when the user
+// hasn't overridden it.
+const defaultBaseURL = "https://api.exercism.io/v1"
+
+// defaultCacheTTL is how long the submission cache is trusted by default.
+const defaultCacheTTL = 30 * 24 * time.Hour
+
+// NewConfig loads the user's persisted configuration from disk, wiring it up
+// with a FilePersister so that any changes a command makes are saved back.
+func NewConfig() Config {
+	v := viper.New()
+	v.SetConfigName("user")
+	v.AddConfigPath(Dir())
+	v.ReadInConfig()
+
+	return Config{
+		Persister:       FilePersister{},
+		UserViperConfig: v,
+		DefaultBaseURL:  defaultBaseURL,
+		Dir:             Dir(),
+		Fs:              afero.NewOsFs(),
+		CacheTTL:        defaultCacheTTL,
+	}
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+)
+
+// Persister writes the user's configuration to durable storage.
+type Persister interface {
+	Save(path string, v *viper.Viper) error
+}
+
+// InMemoryPersister is a Persister that discards what it's given. It exists
+// so tests don't have to touch the real filesystem to exercise code paths
+// that save configuration.
+type InMemoryPersister struct{}
+
+// Save is a no-op.
+func (InMemoryPersister) Save(path string, v *viper.Viper) error {
+	return nil
+}
+
+// FilePersister saves configuration to the user's config directory.
+type FilePersister struct{}
+
+// Save writes v's settings to path.
+func (FilePersister) Save(path string, v *viper.Viper) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.FileMode(0755)); err != nil {
+		return err
+	}
+	return v.WriteConfigAs(path)
+}
+
+// Dir is the directory the CLI stores its own configuration in, as opposed
+// to the user's exercism workspace.
+func Dir() string {
+	if dir := os.Getenv("EXERCISM_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".exercism"
+	}
+	return filepath.Join(home, ".exercism")
+}
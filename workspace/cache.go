@@ -0,0 +1,104 @@
+package workspace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// cacheFilename is where the submission cache is stored, relative to the
+// exercise directory.
+const cacheFilename = ".exercism/cache.json"
+
+// CacheEntry records what we knew about a single submitted file the last
+// time it was part of a submission.
+type CacheEntry struct {
+	SHA256          string    `json:"sha256"`
+	Size            int64     `json:"size"`
+	ModTime         time.Time `json:"mtime"`
+	LastSubmittedAt time.Time `json:"last_submitted_at"`
+}
+
+// Cache is a manifest of the files most recently submitted for a solution,
+// keyed by path relative to the exercise root. It lets `exercism submit`
+// recognize when nothing has changed since the last run.
+type Cache struct {
+	SolutionID string                `json:"solution_id"`
+	CreatedAt  time.Time             `json:"created_at"`
+	Files      map[string]CacheEntry `json:"files"`
+}
+
+// LoadCache reads the exercise's submission cache. A cache that doesn't
+// exist yet, or that belongs to a different solution than solutionID (the
+// exercise was re-downloaded, say), is reported as a fresh, empty cache
+// rather than an error.
+func LoadCache(fs afero.Fs, exercise Exercise, solutionID string) (*Cache, error) {
+	empty := &Cache{SolutionID: solutionID, Files: map[string]CacheEntry{}}
+
+	path := filepath.Join(exercise.Root, cacheFilename)
+	ok, err := afero.Exists(fs, path)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return empty, nil
+	}
+
+	b, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+	var c Cache
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	if c.SolutionID != solutionID {
+		return empty, nil
+	}
+	if c.Files == nil {
+		c.Files = map[string]CacheEntry{}
+	}
+	return &c, nil
+}
+
+// Stale reports whether the cache is older than ttl and should be treated
+// as empty. A zero ttl disables expiry.
+func (c *Cache) Stale(ttl time.Duration) bool {
+	return ttl > 0 && !c.CreatedAt.IsZero() && time.Since(c.CreatedAt) > ttl
+}
+
+// Save writes the cache to the exercise's .exercism directory.
+func (c *Cache) Save(fs afero.Fs, exercise Exercise) error {
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now()
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(exercise.Root, cacheFilename)
+	if err := fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return afero.WriteFile(fs, path, b, 0600)
+}
+
+// HashFile returns the hex-encoded sha256 of the file at path.
+func HashFile(fs afero.Fs, path string) (string, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
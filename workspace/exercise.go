@@ -0,0 +1,93 @@
+// Package workspace locates and describes the exercises a user has
+// downloaded into their local Exercism workspace.
+package workspace
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// Exercise is a downloaded exercise, rooted at a directory somewhere inside
+// the user's workspace.
+type Exercise struct {
+	Root  string
+	Track string
+	Slug  string
+}
+
+// NewExerciseFromDir infers an Exercise from its directory on disk. The
+// track and exercise slug are taken from the last two path components,
+// which is where `exercism download` and the website both place them.
+func NewExerciseFromDir(dir string) Exercise {
+	return Exercise{
+		Root:  dir,
+		Track: filepath.Base(filepath.Dir(dir)),
+		Slug:  filepath.Base(dir),
+	}
+}
+
+// NewExerciseFromFile locates the exercise that a submitted file belongs to
+// by walking up from the file's directory until it finds solution metadata.
+func NewExerciseFromFile(fs afero.Fs, file string) (Exercise, error) {
+	dir := filepath.Dir(file)
+	for {
+		ex := NewExerciseFromDir(dir)
+		if ok, _ := afero.Exists(fs, ex.MetadataFilepath()); ok {
+			return ex, nil
+		}
+		if ok, _ := afero.Exists(fs, ex.LegacyMetadataFilepath()); ok {
+			return ex, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return Exercise{}, fmt.Errorf("%s doesn't have the necessary metadata for submission; please download the exercise again", file)
+}
+
+// MetadataFilepath is the absolute path to the exercise's solution metadata.
+func (e Exercise) MetadataFilepath() string {
+	return filepath.Join(e.Root, metadataFilename)
+}
+
+// LegacyMetadataFilepath is the absolute path to the exercise's pre-migration
+// solution metadata.
+func (e Exercise) LegacyMetadataFilepath() string {
+	return filepath.Join(e.Root, legacyMetadataFilename)
+}
+
+// Solution reads the exercise's solution metadata, migrating it from the
+// legacy location first if necessary.
+func (e Exercise) Solution(fs afero.Fs) (*Solution, error) {
+	if err := e.migrateLegacyMetadata(fs); err != nil {
+		return nil, err
+	}
+	return readSolution(fs, e.MetadataFilepath())
+}
+
+// migrateLegacyMetadata moves solution metadata from the old
+// .solution.json location to the current .exercism/metadata.json location.
+func (e Exercise) migrateLegacyMetadata(fs afero.Fs) error {
+	legacyPath := e.LegacyMetadataFilepath()
+	ok, err := afero.Exists(fs, legacyPath)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	s, err := readSolution(fs, legacyPath)
+	if err != nil {
+		return err
+	}
+	if err := s.Write(fs, e.Root); err != nil {
+		return err
+	}
+	return fs.Remove(legacyPath)
+}
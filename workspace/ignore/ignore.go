@@ -0,0 +1,199 @@
+// Package ignore implements gitignore-style path matching, so that commands
+// which walk an exercise directory (submit, download) can share a single
+// definition of which files to skip.
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// FileName is the name of the file, placed at the root of an exercise (or
+// any of its subdirectories), that lists the patterns to ignore.
+const FileName = ".exercismignore"
+
+// Matcher decides whether a path relative to some root should be excluded,
+// based on an ordered list of gitignore-style patterns. As in gitignore,
+// later patterns take precedence over earlier ones, which lets a `!pattern`
+// re-include something an earlier pattern excluded.
+type Matcher struct {
+	rules []rule
+}
+
+type rule struct {
+	negate  bool
+	dirOnly bool
+	re      *regexp.Regexp
+}
+
+// New compiles a Matcher from a list of raw pattern lines (as they'd appear
+// in a .exercismignore file). Blank lines and lines starting with `#` are
+// ignored.
+func New(lines []string) (*Matcher, error) {
+	m := &Matcher{}
+	for _, line := range lines {
+		r, ok, err := parseLine(line)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			m.rules = append(m.rules, r)
+		}
+	}
+	return m, nil
+}
+
+// LoadTree builds a Matcher out of every .exercismignore found under root,
+// including ones nested in subdirectories. A pattern in a nested ignore
+// file is scoped to that subdirectory and is applied after (so it can
+// override) the patterns from its ancestors.
+func LoadTree(fs afero.Fs, root string) (*Matcher, error) {
+	var lines []string
+	err := afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		raw, err := readLines(fs, filepath.Join(path, FileName))
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, line := range raw {
+			lines = append(lines, scope(line, rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return New(lines)
+}
+
+// Ignores reports whether relPath (slash-separated, relative to the root the
+// Matcher was built from) should be excluded. isDir indicates whether the
+// path is a directory, which matters for patterns with a trailing `/`.
+func (m *Matcher) Ignores(relPath string, isDir bool) bool {
+	relPath = filepath.ToSlash(relPath)
+	ignored := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		if r.re.MatchString(relPath) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+func readLines(fs afero.Fs, path string) ([]string, error) {
+	b, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimRight(string(b), "\n"), "\n"), nil
+}
+
+// scope rewrites a pattern read from the .exercismignore found in the
+// subdirectory rel (relative to the tree root) so that it only ever matches
+// within that subdirectory.
+func scope(line, rel string) string {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") || rel == "." {
+		return line
+	}
+
+	negate := strings.HasPrefix(trimmed, "!")
+	if negate {
+		trimmed = trimmed[1:]
+	}
+	scoped := filepath.ToSlash(filepath.Join(rel, trimmed))
+	if strings.HasSuffix(trimmed, "/") {
+		// filepath.Join drops a trailing slash; keep the pattern dir-only.
+		scoped += "/"
+	}
+	if negate {
+		scoped = "!" + scoped
+	}
+	return scoped
+}
+
+func parseLine(line string) (rule, bool, error) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return rule{}, false, nil
+	}
+
+	r := rule{}
+	pattern := trimmed
+	if strings.HasPrefix(pattern, "!") {
+		r.negate = true
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "/") {
+		r.dirOnly = true
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	re, err := compilePattern(pattern, anchored)
+	if err != nil {
+		return rule{}, false, err
+	}
+	r.re = re
+	return r, true, nil
+}
+
+// compilePattern translates a single gitignore-style pattern into a regular
+// expression that matches a slash-separated relative path.
+func compilePattern(pattern string, anchored bool) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case c == '*' && i+1 < len(runes) && runes[i+1] == '*':
+			i++
+			if i+1 < len(runes) && runes[i+1] == '/' {
+				b.WriteString("(?:.*/)?")
+				i++
+			} else {
+				b.WriteString(".*")
+			}
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case strings.ContainsRune(`\.+()|^$[]{}`, c):
+			b.WriteString("\\")
+			b.WriteRune(c)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	b.WriteString("(?:/.*)?$")
+
+	return regexp.Compile(b.String())
+}
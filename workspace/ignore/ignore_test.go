@@ -0,0 +1,63 @@
+package ignore
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatcherBasicPatterns(t *testing.T) {
+	m, err := New([]string{
+		"*.md",
+		"build/",
+		"/config.local.json",
+	})
+	assert.NoError(t, err)
+
+	assert.True(t, m.Ignores("README.md", false))
+	assert.True(t, m.Ignores("docs/README.md", false))
+	assert.True(t, m.Ignores("build", true))
+	assert.False(t, m.Ignores("build", false), "dir-only pattern shouldn't match a file of the same name")
+	assert.True(t, m.Ignores("config.local.json", false))
+	assert.False(t, m.Ignores("nested/config.local.json", false), "leading slash anchors to the root")
+}
+
+func TestMatcherNegation(t *testing.T) {
+	m, err := New([]string{
+		"*.log",
+		"!important.log",
+	})
+	assert.NoError(t, err)
+
+	assert.True(t, m.Ignores("debug.log", false))
+	assert.False(t, m.Ignores("important.log", false))
+}
+
+func TestMatcherDoubleStar(t *testing.T) {
+	m, err := New([]string{
+		"**/fixtures/**",
+	})
+	assert.NoError(t, err)
+
+	assert.True(t, m.Ignores("fixtures/a.txt", false))
+	assert.True(t, m.Ignores("a/b/fixtures/c.txt", false))
+	assert.False(t, m.Ignores("fixturesnot/a.txt", false))
+}
+
+func TestLoadTreeNested(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	root := "/workspace/bogus-track/bogus-exercise"
+
+	assert.NoError(t, fs.MkdirAll(filepath.Join(root, "subdir"), 0755))
+	assert.NoError(t, afero.WriteFile(fs, filepath.Join(root, FileName), []byte("*.log\n"), 0644))
+	assert.NoError(t, afero.WriteFile(fs, filepath.Join(root, "subdir", FileName), []byte("!keep.log\n"), 0644))
+
+	m, err := LoadTree(fs, root)
+	assert.NoError(t, err)
+
+	assert.True(t, m.Ignores("debug.log", false))
+	assert.True(t, m.Ignores("subdir/debug.log", false))
+	assert.False(t, m.Ignores("subdir/keep.log", false), "the nested ignore file's negation should win over the root pattern")
+}
@@ -0,0 +1,80 @@
+package workspace
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// resumeFilename is where a half-finished submission's progress is
+// recorded, relative to the exercise directory.
+const resumeFilename = ".exercism/resume.json"
+
+// ResumeState records how far a submission got before it was interrupted,
+// so the next `exercism submit` can pick up where it left off instead of
+// re-uploading bytes the server already has. It's only trusted when
+// Resumable is true (the server advertised support for it on the attempt
+// that saved this state) and the solution and payload size it was recorded
+// against still match.
+type ResumeState struct {
+	SolutionID    string `json:"solution_id"`
+	TotalSize     int64  `json:"total_size"`
+	UploadedBytes int64  `json:"uploaded_bytes"`
+	Resumable     bool   `json:"resumable"`
+}
+
+// LoadResumeState reads the exercise's resume state. A missing file is
+// reported as a nil state rather than an error.
+func LoadResumeState(fs afero.Fs, exercise Exercise) (*ResumeState, error) {
+	path := filepath.Join(exercise.Root, resumeFilename)
+	ok, err := afero.Exists(fs, path)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	b, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+	var s ResumeState
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// SaveResumeState persists how much of a submission the server has
+// acknowledged so far.
+func SaveResumeState(fs afero.Fs, exercise Exercise, solutionID string, totalSize, uploadedBytes int64, resumable bool) error {
+	s := ResumeState{
+		SolutionID:    solutionID,
+		TotalSize:     totalSize,
+		UploadedBytes: uploadedBytes,
+		Resumable:     resumable,
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(exercise.Root, resumeFilename)
+	if err := fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return afero.WriteFile(fs, path, b, 0600)
+}
+
+// ClearResumeState removes any resume state left over from a previous,
+// now-irrelevant submission attempt.
+func ClearResumeState(fs afero.Fs, exercise Exercise) error {
+	path := filepath.Join(exercise.Root, resumeFilename)
+	err := fs.Remove(path)
+	if err != nil && os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
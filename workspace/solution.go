@@ -0,0 +1,55 @@
+package workspace
+
+import (
+	"encoding/json"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// metadataFilename is where a solution's metadata is stored, relative to the
+// exercise directory.
+const metadataFilename = ".exercism/metadata.json"
+
+// legacyMetadataFilename is where older versions of the CLI used to store a
+// solution's metadata. It is migrated to metadataFilename the next time the
+// exercise is touched.
+const legacyMetadataFilename = ".solution.json"
+
+// Solution captures the metadata the API needs in order to associate a set
+// of submitted files with a particular exercise and user.
+type Solution struct {
+	ID          string `json:"id"`
+	Track       string `json:"track"`
+	Exercise    string `json:"exercise"`
+	URL         string `json:"url"`
+	IsRequester bool   `json:"is_requester"`
+	AutoApprove bool   `json:"auto_approve,omitempty"`
+}
+
+// Write stores the solution's metadata in the given exercise directory.
+func (s *Solution) Write(fs afero.Fs, dir string) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, metadataFilename)
+	if err := fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return afero.WriteFile(fs, path, b, 0600)
+}
+
+// readSolution reads solution metadata from the file at path, which may be
+// either the current or the legacy metadata location.
+func readSolution(fs afero.Fs, path string) (*Solution, error) {
+	b, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+	var s Solution
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}